@@ -0,0 +1,679 @@
+// Package ipsonar is the Go SDK for the ip-sonar IP geolocation API.
+//
+// This file contains the API client in the shape oapi-codegen produces
+// from the service's OpenAPI spec. Lookup and BatchLookup here also carry
+// the cache and fallback handling; the other extensions (retry, chunked
+// batching, content negotiation, observation) live in sibling files in
+// this package.
+package ipsonar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// API_SERVER is the default base URL for the hosted ip-sonar API.
+	API_SERVER = "https://api.ip-sonar.io/"
+
+	// API_KEY_HEADER is the HTTP header used to authenticate requests.
+	API_KEY_HEADER = "X-API-Key"
+)
+
+// IPGeolocation is the geolocation record returned for a single IP address.
+// Every field is optional: the API omits fields that are not available for
+// a given address, or that the caller did not request via LookupParams.Fields.
+type IPGeolocation struct {
+	IP               *string  `json:"ip,omitempty"`
+	CountryCode      *string  `json:"country_code,omitempty"`
+	CountryName      *string  `json:"country_name,omitempty"`
+	CityName         *string  `json:"city_name,omitempty"`
+	ContinentCode    *string  `json:"continent_code,omitempty"`
+	ContinentName    *string  `json:"continent_name,omitempty"`
+	Latitude         *float32 `json:"latitude,omitempty"`
+	Longitude        *float32 `json:"longitude,omitempty"`
+	Timezone         *string  `json:"timezone,omitempty"`
+	PostalCode       *string  `json:"postal_code,omitempty"`
+	AccuracyRadius   *int32   `json:"accuracy_radius,omitempty"`
+	IsInEu           *bool    `json:"is_in_eu,omitempty"`
+	Subdivision1Code *string  `json:"subdivision1_code,omitempty"`
+	Subdivision1Name *string  `json:"subdivision1_name,omitempty"`
+	Subdivision2Code *string  `json:"subdivision2_code,omitempty"`
+	Subdivision2Name *string  `json:"subdivision2_name,omitempty"`
+}
+
+// ErrorResponse is the body returned alongside non-2xx status codes.
+type ErrorResponse struct {
+	Message string `json:"message"`
+}
+
+// LookupParams holds the query parameters for Client.Lookup.
+type LookupParams struct {
+	// Fields restricts the response to a comma-separated list of field names.
+	Fields *string `form:"fields,omitempty" json:"fields,omitempty"`
+	// LocaleCode selects the language for localized names (e.g. "en", "de").
+	LocaleCode *string `form:"locale_code,omitempty" json:"locale_code,omitempty"`
+}
+
+// LookupMyParams holds the query parameters for Client.LookupMy.
+type LookupMyParams struct {
+	Fields     *string `form:"fields,omitempty" json:"fields,omitempty"`
+	LocaleCode *string `form:"locale_code,omitempty" json:"locale_code,omitempty"`
+}
+
+// BatchLookupParams holds the query parameters for Client.BatchLookup.
+type BatchLookupParams struct {
+	Fields     *string `form:"fields,omitempty" json:"fields,omitempty"`
+	LocaleCode *string `form:"locale_code,omitempty" json:"locale_code,omitempty"`
+}
+
+// BatchLookupBody is the request body for Client.BatchLookup: the list of
+// IP addresses to resolve.
+type BatchLookupBody struct {
+	Data []string `json:"data"`
+}
+
+// BatchLookupJSONRequestBody is the JSON request body for BatchLookup.
+type BatchLookupJSONRequestBody = BatchLookupBody
+
+// BatchLookupIPResponse is the successful response body for BatchLookup.
+type BatchLookupIPResponse struct {
+	Data []IPGeolocation `json:"data"`
+}
+
+// RequestEditorFn is called before a request is sent, allowing callers to
+// set headers, query parameters, or otherwise mutate the outgoing request.
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// HttpRequestDoer performs HTTP requests, satisfied by *http.Client.
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client is a client for the ip-sonar API.
+type Client struct {
+	// Server is the base URL for API requests, always ending in "/".
+	Server string
+	// Client is the HTTP client used to make requests.
+	Client HttpRequestDoer
+	// RequestEditors are applied, in order, to every outgoing request.
+	RequestEditors []RequestEditorFn
+
+	// fallback, if set via WithFallback or WithLocalDatabase, serves
+	// LookupOffline on demand, and is also consulted automatically by
+	// Lookup and BatchLookup whenever the API call fails outright or
+	// returns a 5xx/429.
+	fallback LookupProvider
+
+	// cache, if set via WithCache, is consulted by Lookup and BatchLookup
+	// before issuing HTTP requests, and populated on 200 responses.
+	cache    Cache
+	cacheTTL time.Duration
+
+	// observer, if set via WithObserver, is notified of cache hits/misses.
+	observer Observer
+}
+
+// ClientOption configures a Client during construction.
+type ClientOption func(*Client) error
+
+// NewClient creates a new Client for the given server base URL.
+func NewClient(server string, opts ...ClientOption) (*Client, error) {
+	client := Client{
+		Server: server,
+	}
+
+	for _, opt := range opts {
+		if err := opt(&client); err != nil {
+			return nil, err
+		}
+	}
+
+	if !strings.HasSuffix(client.Server, "/") {
+		client.Server += "/"
+	}
+
+	if client.Client == nil {
+		client.Client = &http.Client{}
+	}
+
+	return &client, nil
+}
+
+// WithHTTPClient overrides the default http.Client used to make requests.
+func WithHTTPClient(doer HttpRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithBaseURL overrides the server base URL set at construction time.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		c.Server = baseURL
+		return nil
+	}
+}
+
+// WithRequestEditorFn adds a RequestEditorFn to be invoked on every
+// outgoing request, e.g. to set an API key header.
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, reqEditors []RequestEditorFn) error {
+	for _, editor := range c.RequestEditors {
+		if err := editor(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, editor := range reqEditors {
+		if err := editor(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func queryString(params url.Values) string {
+	if len(params) == 0 {
+		return ""
+	}
+	return "?" + params.Encode()
+}
+
+// isRetriableStatus reports whether status is one that Lookup and
+// BatchLookup treat as an API-unavailable condition worth falling back
+// from, alongside an outright transport error.
+func isRetriableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// tryFallback reports whether the result of a Lookup API call should
+// instead be served from the configured fallback provider: a transport
+// error, or a 5xx/429 response. It is a no-op if no fallback is
+// configured or the fallback lookup itself fails, leaving resp/err to
+// surface unchanged.
+func (c *Client) tryFallback(ctx context.Context, resp *http.Response, err error, ip string) (*IPGeolocation, bool) {
+	if c.fallback == nil || (err == nil && !isRetriableStatus(resp.StatusCode)) {
+		return nil, false
+	}
+	geo, fbErr := c.fallback.Lookup(ctx, ip)
+	if fbErr != nil {
+		return nil, false
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return geo, true
+}
+
+// Lookup resolves the geolocation of a single IP address. If a Cache is
+// configured, it is consulted first and populated from 200 responses;
+// the cache only ever stores/serves JSON, and Lookup does not inspect the
+// request's Accept header, so non-JSON formats go through LookupRaw. If
+// the API call fails outright or returns a 5xx/429 and a fallback
+// provider is configured (see WithFallback), the lookup is served from
+// it instead.
+func (c *Client) Lookup(ctx context.Context, ip string, params *LookupParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	if c.cache != nil {
+		geo, hit := c.cache.Get(lookupCacheKey(ip, params))
+		if c.observer != nil {
+			c.observer.ObserveCacheEvent(ctx, "lookup", hit)
+		}
+		if hit {
+			return jsonHTTPResponse(http.StatusOK, geo)
+		}
+	}
+
+	resp, err := c.LookupRaw(ctx, ip, params, reqEditors...)
+	if geo, ok := c.tryFallback(ctx, resp, err, ip); ok {
+		return jsonHTTPResponse(http.StatusOK, geo)
+	}
+	if err != nil || c.cache == nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("ipsonar: read lookup response body: %w", err)
+	}
+
+	var geo IPGeolocation
+	if err := json.Unmarshal(bodyBytes, &geo); err == nil {
+		c.cache.Set(lookupCacheKey(ip, params), geo, c.cacheTTL)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+	return resp, nil
+}
+
+// LookupRaw issues a single-IP lookup directly against the API, bypassing
+// any configured Cache entirely. Content-negotiated response parsers
+// (see LookupTextWithResponse) use this, since the cache only ever
+// stores decoded JSON results.
+func (c *Client) LookupRaw(ctx context.Context, ip string, params *LookupParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewLookupRequest(c.Server, ip, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req.WithContext(ctx))
+}
+
+// LookupMy resolves the geolocation of the caller's own IP address.
+func (c *Client) LookupMy(ctx context.Context, params *LookupMyParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewLookupMyRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req.WithContext(ctx))
+}
+
+// tryBatchFallback mirrors tryFallback for BatchLookup, resolving every
+// IP in ips from the configured fallback provider. It fails closed: if
+// any individual lookup errors, it reports false and resp/err surface
+// unchanged rather than returning a partial result.
+func (c *Client) tryBatchFallback(ctx context.Context, resp *http.Response, err error, ips []string) ([]IPGeolocation, bool) {
+	if c.fallback == nil || (err == nil && !isRetriableStatus(resp.StatusCode)) {
+		return nil, false
+	}
+	geos := make([]IPGeolocation, len(ips))
+	for i, ip := range ips {
+		geo, fbErr := c.fallback.Lookup(ctx, ip)
+		if fbErr != nil {
+			return nil, false
+		}
+		geos[i] = *geo
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return geos, true
+}
+
+// BatchLookup resolves the geolocation of up to the server's supported
+// batch size of IP addresses in a single request. If a Cache is
+// configured, cached IPs are served without a network round trip and
+// only the misses are sent to the API. If the API call fails outright or
+// returns a 5xx/429 and a fallback provider is configured (see
+// WithFallback), the misses are served from it instead.
+func (c *Client) BatchLookup(ctx context.Context, params *BatchLookupParams, body BatchLookupJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	if c.cache == nil {
+		resp, err := c.BatchLookupRaw(ctx, params, body, reqEditors...)
+		if geos, ok := c.tryBatchFallback(ctx, resp, err, body.Data); ok {
+			return jsonHTTPResponse(http.StatusOK, BatchLookupIPResponse{Data: geos})
+		}
+		return resp, err
+	}
+
+	cached := make(map[string]IPGeolocation, len(body.Data))
+	var misses []string
+	for _, ip := range body.Data {
+		geo, hit := c.cache.Get(batchCacheKey(ip, params))
+		if c.observer != nil {
+			c.observer.ObserveCacheEvent(ctx, "batch_lookup", hit)
+		}
+		if hit {
+			cached[ip] = *geo
+			continue
+		}
+		misses = append(misses, ip)
+	}
+
+	if len(misses) == 0 {
+		return jsonHTTPResponse(http.StatusOK, BatchLookupIPResponse{Data: orderedGeolocations(body.Data, cached)})
+	}
+
+	resp, err := c.BatchLookupRaw(ctx, params, BatchLookupJSONRequestBody{Data: misses}, reqEditors...)
+	if geos, ok := c.tryBatchFallback(ctx, resp, err, misses); ok {
+		for i, ip := range misses {
+			cached[ip] = geos[i]
+		}
+		return jsonHTTPResponse(http.StatusOK, BatchLookupIPResponse{Data: orderedGeolocations(body.Data, cached)})
+	}
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("ipsonar: read batch lookup response body: %w", err)
+	}
+
+	var fetched BatchLookupIPResponse
+	if err := json.Unmarshal(bodyBytes, &fetched); err != nil {
+		return nil, fmt.Errorf("ipsonar: decode batch lookup response: %w", err)
+	}
+
+	for i, ip := range misses {
+		if i >= len(fetched.Data) {
+			break
+		}
+		geo := fetched.Data[i]
+		cached[ip] = geo
+		c.cache.Set(batchCacheKey(ip, params), geo, c.cacheTTL)
+	}
+
+	return jsonHTTPResponse(http.StatusOK, BatchLookupIPResponse{Data: orderedGeolocations(body.Data, cached)})
+}
+
+// BatchLookupRaw issues a batch lookup directly against the API,
+// bypassing any configured Cache entirely. Content-negotiated response
+// parsers (see BatchLookupCSVWithResponse) use this, since the cache
+// only ever stores decoded JSON results.
+func (c *Client) BatchLookupRaw(ctx context.Context, params *BatchLookupParams, body BatchLookupJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewBatchLookupRequest(c.Server, params, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req.WithContext(ctx))
+}
+
+// NewLookupRequest builds a GET request for a single-IP lookup.
+func NewLookupRequest(server string, ip string, params *LookupParams) (*http.Request, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Fields != nil {
+			query.Set("fields", *params.Fields)
+		}
+		if params.LocaleCode != nil {
+			query.Set("locale_code", *params.LocaleCode)
+		}
+	}
+	u := fmt.Sprintf("%s%s%s", server, url.PathEscape(ip), queryString(query))
+	return http.NewRequest(http.MethodGet, u, nil)
+}
+
+// NewLookupMyRequest builds a GET request for a self-IP lookup.
+func NewLookupMyRequest(server string, params *LookupMyParams) (*http.Request, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Fields != nil {
+			query.Set("fields", *params.Fields)
+		}
+		if params.LocaleCode != nil {
+			query.Set("locale_code", *params.LocaleCode)
+		}
+	}
+	u := fmt.Sprintf("%smy%s", server, queryString(query))
+	return http.NewRequest(http.MethodGet, u, nil)
+}
+
+// NewBatchLookupRequest builds a POST request for a batch lookup.
+func NewBatchLookupRequest(server string, params *BatchLookupParams, body BatchLookupJSONRequestBody) (*http.Request, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Fields != nil {
+			query.Set("fields", *params.Fields)
+		}
+		if params.LocaleCode != nil {
+			query.Set("locale_code", *params.LocaleCode)
+		}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshal batch lookup body: %w", err)
+	}
+	u := fmt.Sprintf("%sbatch%s", server, queryString(query))
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// ClientInterface is the set of operations exposed by Client, abstracted so
+// that ClientWithResponses can be built against either a real Client or a
+// test double.
+type ClientInterface interface {
+	Lookup(ctx context.Context, ip string, params *LookupParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+	LookupMy(ctx context.Context, params *LookupMyParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+	BatchLookup(ctx context.Context, params *BatchLookupParams, body BatchLookupJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// LookupRaw and BatchLookupRaw bypass any configured Cache; they back
+	// the content-negotiated text/CSV response parsers, since the cache
+	// only ever stores decoded JSON results.
+	LookupRaw(ctx context.Context, ip string, params *LookupParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+	BatchLookupRaw(ctx context.Context, params *BatchLookupParams, body BatchLookupJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+// ClientWithResponses wraps ClientInterface with response parsing, so
+// callers get typed, decoded results instead of raw *http.Response values.
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses for the given
+// server base URL.
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{ClientInterface: client}, nil
+}
+
+// LookupResponse is the parsed response from LookupWithResponse.
+type LookupResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *IPGeolocation
+	JSON401      *ErrorResponse
+	JSON404      *ErrorResponse
+	JSON429      *ErrorResponse
+}
+
+// StatusCode returns the HTTP status code of the response.
+func (r *LookupResponse) StatusCode() int {
+	if r.HTTPResponse == nil {
+		return 0
+	}
+	return r.HTTPResponse.StatusCode
+}
+
+// Status returns the HTTP status line of the response.
+func (r *LookupResponse) Status() string {
+	if r.HTTPResponse == nil {
+		return ""
+	}
+	return r.HTTPResponse.Status
+}
+
+// LookupMyResponse is the parsed response from LookupMyWithResponse.
+type LookupMyResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *IPGeolocation
+	JSON401      *ErrorResponse
+	JSON404      *ErrorResponse
+	JSON429      *ErrorResponse
+}
+
+// StatusCode returns the HTTP status code of the response.
+func (r *LookupMyResponse) StatusCode() int {
+	if r.HTTPResponse == nil {
+		return 0
+	}
+	return r.HTTPResponse.StatusCode
+}
+
+// Status returns the HTTP status line of the response.
+func (r *LookupMyResponse) Status() string {
+	if r.HTTPResponse == nil {
+		return ""
+	}
+	return r.HTTPResponse.Status
+}
+
+// BatchLookupResponse is the parsed response from BatchLookupWithResponse.
+type BatchLookupResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *BatchLookupIPResponse
+	JSON401      *ErrorResponse
+	JSON429      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// StatusCode returns the HTTP status code of the response.
+func (r *BatchLookupResponse) StatusCode() int {
+	if r.HTTPResponse == nil {
+		return 0
+	}
+	return r.HTTPResponse.StatusCode
+}
+
+// Status returns the HTTP status line of the response.
+func (r *BatchLookupResponse) Status() string {
+	if r.HTTPResponse == nil {
+		return ""
+	}
+	return r.HTTPResponse.Status
+}
+
+// LookupWithResponse calls Lookup and parses the result into a LookupResponse.
+func (c *ClientWithResponses) LookupWithResponse(ctx context.Context, ip string, params *LookupParams, reqEditors ...RequestEditorFn) (*LookupResponse, error) {
+	rsp, err := c.ClientInterface.Lookup(ctx, ip, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	return ParseLookupResponse(rsp)
+}
+
+// LookupMyWithResponse calls LookupMy and parses the result into a LookupMyResponse.
+func (c *ClientWithResponses) LookupMyWithResponse(ctx context.Context, params *LookupMyParams, reqEditors ...RequestEditorFn) (*LookupMyResponse, error) {
+	rsp, err := c.ClientInterface.LookupMy(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	return ParseLookupMyResponse(rsp)
+}
+
+// BatchLookupWithResponse calls BatchLookup and parses the result into a BatchLookupResponse.
+func (c *ClientWithResponses) BatchLookupWithResponse(ctx context.Context, params *BatchLookupParams, body BatchLookupJSONRequestBody, reqEditors ...RequestEditorFn) (*BatchLookupResponse, error) {
+	rsp, err := c.ClientInterface.BatchLookup(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	return ParseBatchLookupResponse(rsp)
+}
+
+// ParseLookupResponse parses an *http.Response from Lookup into a LookupResponse.
+func ParseLookupResponse(rsp *http.Response) (*LookupResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read lookup response body: %w", err)
+	}
+
+	result := &LookupResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch rsp.StatusCode {
+	case http.StatusOK:
+		var dest IPGeolocation
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, fmt.Errorf("decode 200 lookup response: %w", err)
+		}
+		result.JSON200 = &dest
+	case http.StatusUnauthorized:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err == nil {
+			result.JSON401 = &dest
+		}
+	case http.StatusNotFound:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err == nil {
+			result.JSON404 = &dest
+		}
+	case http.StatusTooManyRequests:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err == nil {
+			result.JSON429 = &dest
+		}
+	}
+
+	return result, nil
+}
+
+// ParseLookupMyResponse parses an *http.Response from LookupMy into a LookupMyResponse.
+func ParseLookupMyResponse(rsp *http.Response) (*LookupMyResponse, error) {
+	lookup, err := ParseLookupResponse(rsp)
+	if err != nil {
+		return nil, err
+	}
+	return &LookupMyResponse{
+		Body:         lookup.Body,
+		HTTPResponse: lookup.HTTPResponse,
+		JSON200:      lookup.JSON200,
+		JSON401:      lookup.JSON401,
+		JSON404:      lookup.JSON404,
+		JSON429:      lookup.JSON429,
+	}, nil
+}
+
+// ParseBatchLookupResponse parses an *http.Response from BatchLookup into a BatchLookupResponse.
+func ParseBatchLookupResponse(rsp *http.Response) (*BatchLookupResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read batch lookup response body: %w", err)
+	}
+
+	result := &BatchLookupResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch rsp.StatusCode {
+	case http.StatusOK:
+		var dest BatchLookupIPResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, fmt.Errorf("decode 200 batch lookup response: %w", err)
+		}
+		result.JSON200 = &dest
+	case http.StatusUnauthorized:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err == nil {
+			result.JSON401 = &dest
+		}
+	case http.StatusTooManyRequests:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err == nil {
+			result.JSON429 = &dest
+		}
+	case http.StatusInternalServerError:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err == nil {
+			result.JSON500 = &dest
+		}
+	}
+
+	return result, nil
+}
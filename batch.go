@@ -0,0 +1,191 @@
+package ipsonar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	defaultChunkSize   = 100
+	defaultConcurrency = 4
+)
+
+// BatchOption configures a Client.BatchLookupAll call.
+type BatchOption func(*batchConfig)
+
+type batchConfig struct {
+	chunkSize   int
+	concurrency int
+}
+
+// WithChunkSize overrides the number of IPs sent to the API per request.
+// Defaults to 100.
+func WithChunkSize(size int) BatchOption {
+	return func(cfg *batchConfig) {
+		if size > 0 {
+			cfg.chunkSize = size
+		}
+	}
+}
+
+// WithConcurrency overrides how many chunk requests are in flight at
+// once. Defaults to 4.
+func WithConcurrency(n int) BatchOption {
+	return func(cfg *batchConfig) {
+		if n > 0 {
+			cfg.concurrency = n
+		}
+	}
+}
+
+// ChunkFailure describes the failure of a single chunk within a
+// BatchLookupAll call.
+type ChunkFailure struct {
+	// StartIndex is the index, within the original ips slice, of this
+	// chunk's first IP.
+	StartIndex int
+	// IPs is the chunk that failed.
+	IPs []string
+	// Err is the error returned for this chunk.
+	Err error
+}
+
+// BatchError is returned by BatchLookupAll when one or more chunks failed
+// but at least one other chunk succeeded. When every chunk fails, the
+// first chunk's error is returned directly instead.
+type BatchError struct {
+	Failures []ChunkFailure
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("ipsonar: batch lookup failed for %d of the requested chunks", len(e.Failures))
+}
+
+// BatchLookupAll splits ips into server-sized chunks, looks them up
+// concurrently, and merges the results into a single
+// BatchLookupIPResponse preserving the input order. It stops dispatching
+// further chunks as soon as one fails and cancels requests still in
+// flight; chunks that completed successfully before that are merged, and
+// genuine failures are reported via a *BatchError (or, when no chunk
+// succeeded, as the first failure's error directly).
+//
+// It is a method on *ClientWithResponses, not *Client: merging chunk
+// results requires the decoded BatchLookupIPResponse that only the
+// WithResponses layer produces, the same reason BatchLookupWithResponse
+// itself lives there rather than on the raw Client.
+func (c *ClientWithResponses) BatchLookupAll(ctx context.Context, ips []string, params *BatchLookupParams, opts ...BatchOption) (*BatchLookupIPResponse, error) {
+	if len(ips) == 0 {
+		return &BatchLookupIPResponse{}, nil
+	}
+
+	cfg := batchConfig{
+		chunkSize:   defaultChunkSize,
+		concurrency: defaultConcurrency,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	type chunkResult struct {
+		startIndex int
+		ips        []string
+		data       []IPGeolocation
+		err        error
+		// skipped marks a chunk that never ran, or whose request was
+		// cancelled mid-flight, because the context had been cancelled;
+		// it is dropped entirely rather than reported as a ChunkFailure.
+		skipped bool
+	}
+
+	chunks := chunkIPs(ips, cfg.chunkSize)
+	results := make([]chunkResult, len(chunks))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, cfg.concurrency)
+
+	for i, chunk := range chunks {
+		i, chunk := i, chunk
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				results[i] = chunkResult{startIndex: chunk.startIndex, ips: chunk.ips, skipped: true}
+				return
+			}
+
+			resp, err := c.BatchLookupWithResponse(ctx, params, BatchLookupJSONRequestBody{Data: chunk.ips})
+			if err == nil && resp.JSON200 == nil {
+				err = fmt.Errorf("ipsonar: batch lookup returned status %d", resp.StatusCode())
+			}
+			if err != nil {
+				if errors.Is(err, context.Canceled) {
+					results[i] = chunkResult{startIndex: chunk.startIndex, ips: chunk.ips, skipped: true}
+					return
+				}
+				results[i] = chunkResult{startIndex: chunk.startIndex, ips: chunk.ips, err: err}
+				cancel()
+				return
+			}
+
+			results[i] = chunkResult{startIndex: chunk.startIndex, ips: chunk.ips, data: resp.JSON200.Data}
+		}()
+	}
+
+	wg.Wait()
+
+	merged := make([]IPGeolocation, len(ips))
+	var failures []ChunkFailure
+	successes := 0
+
+	for _, r := range results {
+		switch {
+		case r.skipped:
+			continue
+		case r.err != nil:
+			failures = append(failures, ChunkFailure{StartIndex: r.startIndex, IPs: r.ips, Err: r.err})
+		default:
+			successes++
+			copy(merged[r.startIndex:r.startIndex+len(r.ips)], r.data)
+		}
+	}
+
+	switch {
+	case len(failures) > 0 && successes == 0:
+		// Nothing came back; surface the first genuine failure directly
+		// rather than a cascaded context.Canceled from a skipped chunk.
+		return nil, failures[0].Err
+	case len(failures) == 0 && successes == 0:
+		// Every chunk was skipped or cancelled, which only happens if the
+		// caller's ctx was done before any chunk completed.
+		return nil, ctx.Err()
+	case len(failures) > 0:
+		return &BatchLookupIPResponse{Data: merged}, &BatchError{Failures: failures}
+	default:
+		return &BatchLookupIPResponse{Data: merged}, nil
+	}
+}
+
+type ipChunk struct {
+	startIndex int
+	ips        []string
+}
+
+func chunkIPs(ips []string, size int) []ipChunk {
+	var chunks []ipChunk
+	for i := 0; i < len(ips); i += size {
+		end := i + size
+		if end > len(ips) {
+			end = len(ips)
+		}
+		chunks = append(chunks, ipChunk{startIndex: i, ips: ips[i:end]})
+	}
+	return chunks
+}
@@ -0,0 +1,202 @@
+package ipsonar_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	. "github.com/ip-sonar/ip-sonar-go"
+)
+
+func TestBatchLookupAll_PreservesOrderAcrossChunks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body BatchLookupJSONRequestBody
+		json.NewDecoder(r.Body).Decode(&body)
+
+		var data []IPGeolocation
+		for _, ip := range body.Data {
+			ip := ip
+			data = append(data, IPGeolocation{IP: &ip})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(BatchLookupIPResponse{Data: data})
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithResponses(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	var ips []string
+	for i := 0; i < 25; i++ {
+		ips = append(ips, string(rune('a'+i)))
+	}
+
+	resp, err := client.BatchLookupAll(context.Background(), ips, nil, WithChunkSize(4), WithConcurrency(3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Data) != len(ips) {
+		t.Fatalf("expected %d results, got %d", len(ips), len(resp.Data))
+	}
+	for i, ip := range ips {
+		if resp.Data[i].IP == nil || *resp.Data[i].IP != ip {
+			t.Errorf("result %d: expected ip %q, got %v", i, ip, resp.Data[i].IP)
+		}
+	}
+}
+
+func TestBatchLookupAll_EmptyInput(t *testing.T) {
+	client, err := NewClientWithResponses("https://api.example.com")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.BatchLookupAll(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(&BatchLookupIPResponse{}, resp); diff != "" {
+		t.Errorf("unexpected response (-want +got):\n%s", diff)
+	}
+}
+
+func TestBatchLookupAll_PartialFailureReturnsBatchError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if n == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var body BatchLookupJSONRequestBody
+		json.NewDecoder(r.Body).Decode(&body)
+		var data []IPGeolocation
+		for _, ip := range body.Data {
+			ip := ip
+			data = append(data, IPGeolocation{IP: &ip})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(BatchLookupIPResponse{Data: data})
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithResponses(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ips := []string{"1.1.1.1", "2.2.2.2", "3.3.3.3", "4.4.4.4"}
+	_, err = client.BatchLookupAll(context.Background(), ips, nil, WithChunkSize(2), WithConcurrency(1))
+
+	var batchErr *BatchError
+	if err == nil {
+		t.Fatal("expected an error but got none")
+	}
+	if !asBatchError(err, &batchErr) {
+		t.Fatalf("expected a *BatchError, got %T: %v", err, err)
+	}
+	if len(batchErr.Failures) != 1 {
+		t.Errorf("expected exactly 1 chunk failure, got %d", len(batchErr.Failures))
+	}
+}
+
+func TestBatchLookupAll_AllChunksFailReturnsFirstError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithResponses(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ips := []string{"1.1.1.1", "2.2.2.2", "3.3.3.3", "4.4.4.4"}
+	resp, err := client.BatchLookupAll(context.Background(), ips, nil, WithChunkSize(2), WithConcurrency(2))
+
+	if resp != nil {
+		t.Errorf("expected nil response when every chunk fails, got %+v", resp)
+	}
+	var batchErr *BatchError
+	if asBatchError(err, &batchErr) {
+		t.Fatalf("expected the raw chunk error, not a *BatchError: %v", err)
+	}
+	if err == nil {
+		t.Fatal("expected an error but got none")
+	}
+}
+
+func TestBatchLookupAll_RespectsConcurrencyLimit(t *testing.T) {
+	var inFlight, maxInFlight int32
+	var mu sync.Mutex
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		mu.Lock()
+		if n > maxInFlight {
+			maxInFlight = n
+		}
+		mu.Unlock()
+		<-release
+		atomic.AddInt32(&inFlight, -1)
+
+		var body BatchLookupJSONRequestBody
+		json.NewDecoder(r.Body).Decode(&body)
+		var data []IPGeolocation
+		for _, ip := range body.Data {
+			ip := ip
+			data = append(data, IPGeolocation{IP: &ip})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(BatchLookupIPResponse{Data: data})
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithResponses(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	var ips []string
+	for i := 0; i < 10; i++ {
+		ips = append(ips, string(rune('a'+i)))
+	}
+
+	done := make(chan struct{})
+	go func() {
+		client.BatchLookupAll(context.Background(), ips, nil, WithChunkSize(1), WithConcurrency(2))
+		close(done)
+	}()
+
+	// Give the workers a moment to saturate the semaphore before releasing
+	// them all at once; the assertion below only checks the high-water mark.
+	for atomic.LoadInt32(&inFlight) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 concurrent chunk requests, saw %d", maxInFlight)
+	}
+}
+
+// asBatchError reports whether err is a *BatchError, populating target if so.
+func asBatchError(err error, target **BatchError) bool {
+	be, ok := err.(*BatchError)
+	if ok {
+		*target = be
+	}
+	return ok
+}
@@ -0,0 +1,245 @@
+package ipsonar
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultCacheTTL is used when WithCache is configured without an
+// explicit WithCacheTTL.
+const defaultCacheTTL = 10 * time.Minute
+
+// Cache is a pluggable store for lookup results, consulted by Client
+// before issuing HTTP requests and populated on 200 responses.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the cached geolocation for key, if present and not expired.
+	Get(key string) (*IPGeolocation, bool)
+	// Set stores value under key, expiring it after ttl.
+	Set(key string, value IPGeolocation, ttl time.Duration)
+	// Invalidate removes every cached entry for ip, regardless of the
+	// fields/locale combination it was stored under.
+	Invalidate(ip string)
+}
+
+// WithCache wires cache into the Client: Lookup and BatchLookup consult
+// it before issuing HTTP requests, keyed on (ip, fields, locale_code),
+// and populate it on 200 responses.
+func WithCache(cache Cache) ClientOption {
+	return func(c *Client) error {
+		c.cache = cache
+		if c.cacheTTL == 0 {
+			c.cacheTTL = defaultCacheTTL
+		}
+		return nil
+	}
+}
+
+// WithCacheTTL overrides the TTL used when populating the cache
+// configured via WithCache. It may be given before or after WithCache.
+func WithCacheTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.cacheTTL = ttl
+		return nil
+	}
+}
+
+func lookupCacheKey(ip string, params *LookupParams) string {
+	var fields, locale string
+	if params != nil {
+		if params.Fields != nil {
+			fields = *params.Fields
+		}
+		if params.LocaleCode != nil {
+			locale = *params.LocaleCode
+		}
+	}
+	return fmt.Sprintf("%s|%s|%s", ip, fields, locale)
+}
+
+func batchCacheKey(ip string, params *BatchLookupParams) string {
+	var fields, locale string
+	if params != nil {
+		if params.Fields != nil {
+			fields = *params.Fields
+		}
+		if params.LocaleCode != nil {
+			locale = *params.LocaleCode
+		}
+	}
+	return fmt.Sprintf("%s|%s|%s", ip, fields, locale)
+}
+
+// ipFromCacheKey extracts the request IP a lookupCacheKey/batchCacheKey
+// was built from, i.e. the part before the first "|". Entries are indexed
+// for Invalidate by this rather than by the response's echoed ip field,
+// since a restricted fields list can omit that field from the response
+// entirely.
+func ipFromCacheKey(key string) string {
+	ip, _, _ := strings.Cut(key, "|")
+	return ip
+}
+
+// orderedGeolocations rebuilds the per-IP results in ips into the order
+// the caller originally requested them.
+func orderedGeolocations(ips []string, byIP map[string]IPGeolocation) []IPGeolocation {
+	result := make([]IPGeolocation, len(ips))
+	for i, ip := range ips {
+		result[i] = byIP[ip]
+	}
+	return result
+}
+
+// jsonHTTPResponse synthesizes a 200-style *http.Response carrying v as
+// its JSON body, used to serve a cache hit without a network round trip.
+func jsonHTTPResponse(status int, v interface{}) (*http.Response, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("ipsonar: marshal cached response: %w", err)
+	}
+
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+
+	return &http.Response{
+		StatusCode:    status,
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(payload)),
+		ContentLength: int64(len(payload)),
+	}, nil
+}
+
+// lruEntry is a single cached item in an *lruCache.
+type lruEntry struct {
+	key       string
+	ip        string
+	value     IPGeolocation
+	expiresAt time.Time
+}
+
+// lruCache is the default Cache: an LRU bounded by entry count, with a
+// per-entry TTL. Safe for concurrent use.
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+	ipToKeys map[string]map[string]struct{}
+
+	hits   uint64
+	misses uint64
+}
+
+// NewLRUCache returns a Cache backed by an in-memory LRU bounded to at
+// most capacity entries. A capacity <= 0 defaults to 10000.
+func NewLRUCache(capacity int) Cache {
+	if capacity <= 0 {
+		capacity = 10000
+	}
+	return &lruCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+		ipToKeys: make(map[string]map[string]struct{}),
+	}
+}
+
+// CacheStats reports cumulative hit/miss counts for an *lruCache.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// Stats returns a snapshot of the cache's cumulative hit/miss counters.
+func (c *lruCache) Stats() CacheStats {
+	return CacheStats{
+		Hits:   atomic.LoadUint64(&c.hits),
+		Misses: atomic.LoadUint64(&c.misses),
+	}
+}
+
+func (c *lruCache) Get(key string) (*IPGeolocation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+
+	entry := el.Value.(*lruEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(el)
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	atomic.AddUint64(&c.hits, 1)
+	value := entry.value
+	return &value, true
+}
+
+func (c *lruCache) Set(key string, value IPGeolocation, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ip := ipFromCacheKey(key)
+
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = time.Now().Add(ttl)
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	entry := &lruEntry{key: key, ip: ip, value: value, expiresAt: time.Now().Add(ttl)}
+	el := c.ll.PushFront(entry)
+	c.items[key] = el
+
+	if c.ipToKeys[ip] == nil {
+		c.ipToKeys[ip] = make(map[string]struct{})
+	}
+	c.ipToKeys[ip][key] = struct{}{}
+
+	if c.ll.Len() > c.capacity {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+func (c *lruCache) Invalidate(ip string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.ipToKeys[ip] {
+		if el, ok := c.items[key]; ok {
+			c.removeElement(el)
+		}
+	}
+	delete(c.ipToKeys, ip)
+}
+
+// removeElement evicts el from both the LRU list and the ip index. The
+// caller must hold c.mu.
+func (c *lruCache) removeElement(el *list.Element) {
+	entry := el.Value.(*lruEntry)
+	c.ll.Remove(el)
+	delete(c.items, entry.key)
+	if keys, ok := c.ipToKeys[entry.ip]; ok {
+		delete(keys, entry.key)
+		if len(keys) == 0 {
+			delete(c.ipToKeys, entry.ip)
+		}
+	}
+}
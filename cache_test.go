@@ -0,0 +1,104 @@
+package ipsonar_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/ip-sonar/ip-sonar-go"
+)
+
+func TestLRUCache_GetSetRoundTrip(t *testing.T) {
+	cache := NewLRUCache(10)
+
+	geo := IPGeolocation{IP: ptr("1.2.3.4"), CountryCode: ptr("US")}
+	cache.Set("1.2.3.4||", geo, time.Minute)
+
+	got, hit := cache.Get("1.2.3.4||")
+	if !hit {
+		t.Fatal("expected a cache hit")
+	}
+	if got.CountryCode == nil || *got.CountryCode != "US" {
+		t.Errorf("expected CountryCode %q, got %v", "US", got.CountryCode)
+	}
+
+	if _, hit := cache.Get("missing||"); hit {
+		t.Error("expected a miss for an unset key")
+	}
+}
+
+func TestLRUCache_TTLExpiry(t *testing.T) {
+	cache := NewLRUCache(10)
+
+	geo := IPGeolocation{IP: ptr("1.2.3.4")}
+	cache.Set("1.2.3.4||", geo, time.Millisecond)
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, hit := cache.Get("1.2.3.4||"); hit {
+		t.Error("expected the entry to have expired")
+	}
+}
+
+func TestLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewLRUCache(2)
+
+	cache.Set("a||", IPGeolocation{IP: ptr("a")}, time.Minute)
+	cache.Set("b||", IPGeolocation{IP: ptr("b")}, time.Minute)
+
+	// Touch "a" so "b" becomes the least recently used entry.
+	cache.Get("a||")
+
+	cache.Set("c||", IPGeolocation{IP: ptr("c")}, time.Minute)
+
+	if _, hit := cache.Get("b||"); hit {
+		t.Error("expected \"b\" to have been evicted as the least recently used entry")
+	}
+	if _, hit := cache.Get("a||"); !hit {
+		t.Error("expected \"a\" to still be cached")
+	}
+	if _, hit := cache.Get("c||"); !hit {
+		t.Error("expected \"c\" to still be cached")
+	}
+}
+
+func TestLRUCache_InvalidateByRequestIP(t *testing.T) {
+	cache := NewLRUCache(10)
+
+	// A response with a restricted fields list can omit the echoed "ip"
+	// field entirely; invalidation must still key off the request IP
+	// embedded in the cache key, not the (possibly absent) response field.
+	cache.Set("1.2.3.4|country_code|", IPGeolocation{CountryCode: ptr("US")}, time.Minute)
+	cache.Set("1.2.3.4|city_name|en", IPGeolocation{CityName: ptr("NYC")}, time.Minute)
+	cache.Set("5.6.7.8||", IPGeolocation{IP: ptr("5.6.7.8")}, time.Minute)
+
+	cache.Invalidate("1.2.3.4")
+
+	if _, hit := cache.Get("1.2.3.4|country_code|"); hit {
+		t.Error("expected the country_code entry for 1.2.3.4 to be invalidated")
+	}
+	if _, hit := cache.Get("1.2.3.4|city_name|en"); hit {
+		t.Error("expected the city_name entry for 1.2.3.4 to be invalidated")
+	}
+	if _, hit := cache.Get("5.6.7.8||"); !hit {
+		t.Error("expected the unrelated 5.6.7.8 entry to remain cached")
+	}
+}
+
+func TestLRUCache_Stats(t *testing.T) {
+	cache := NewLRUCache(10).(interface {
+		Cache
+		Stats() CacheStats
+	})
+
+	cache.Set("1.2.3.4||", IPGeolocation{IP: ptr("1.2.3.4")}, time.Minute)
+	cache.Get("1.2.3.4||")
+	cache.Get("missing||")
+
+	stats := cache.Stats()
+	if stats.Hits != 1 {
+		t.Errorf("expected 1 hit, got %d", stats.Hits)
+	}
+	if stats.Misses != 1 {
+		t.Errorf("expected 1 miss, got %d", stats.Misses)
+	}
+}
@@ -0,0 +1,146 @@
+package ipsonar
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// LookupProvider is a source of geolocation data that a Client can fall
+// back to when the ip-sonar API is unreachable or returns a retriable
+// error (5xx or 429). Implementations must be safe for concurrent use.
+type LookupProvider interface {
+	// Lookup resolves the geolocation of ip, returning the same shape the
+	// API would for a successful lookup.
+	Lookup(ctx context.Context, ip string) (*IPGeolocation, error)
+	// Close releases any resources held by the provider, e.g. an open
+	// database file.
+	Close() error
+}
+
+// mmdbProvider is a LookupProvider backed by a local MaxMind GeoIP2
+// database (e.g. GeoLite2-City).
+type mmdbProvider struct {
+	reader *geoip2.Reader
+}
+
+// NewLocalDatabase opens the MaxMind .mmdb file at path and returns a
+// LookupProvider backed by it. The caller is responsible for calling
+// Close when the provider is no longer needed.
+func NewLocalDatabase(path string) (LookupProvider, error) {
+	reader, err := geoip2.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ipsonar: open mmdb %q: %w", path, err)
+	}
+	return &mmdbProvider{reader: reader}, nil
+}
+
+func (p *mmdbProvider) Close() error {
+	return p.reader.Close()
+}
+
+func (p *mmdbProvider) Lookup(_ context.Context, ip string) (*IPGeolocation, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, fmt.Errorf("ipsonar: invalid IP address %q", ip)
+	}
+
+	record, err := p.reader.City(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("ipsonar: mmdb lookup %q: %w", ip, err)
+	}
+
+	geo := &IPGeolocation{IP: &ip}
+
+	if name := record.City.Names["en"]; name != "" {
+		geo.CityName = &name
+	}
+	if code := record.Country.IsoCode; code != "" {
+		geo.CountryCode = &code
+	}
+	if name := record.Country.Names["en"]; name != "" {
+		geo.CountryName = &name
+	}
+	if code := record.Continent.Code; code != "" {
+		geo.ContinentCode = &code
+	}
+	if name := record.Continent.Names["en"]; name != "" {
+		geo.ContinentName = &name
+	}
+	if len(record.Subdivisions) > 0 {
+		sub := record.Subdivisions[0]
+		if sub.IsoCode != "" {
+			geo.Subdivision1Code = &sub.IsoCode
+		}
+		if name := sub.Names["en"]; name != "" {
+			geo.Subdivision1Name = &name
+		}
+	}
+	if len(record.Subdivisions) > 1 {
+		sub := record.Subdivisions[1]
+		if sub.IsoCode != "" {
+			geo.Subdivision2Code = &sub.IsoCode
+		}
+		if name := sub.Names["en"]; name != "" {
+			geo.Subdivision2Name = &name
+		}
+	}
+	// record.Location is always present on a City lookup, and 0 is a
+	// valid latitude/longitude/accuracy radius, so map these
+	// unconditionally rather than treating zero as "absent".
+	lat := float32(record.Location.Latitude)
+	geo.Latitude = &lat
+	lon := float32(record.Location.Longitude)
+	geo.Longitude = &lon
+	radius := int32(record.Location.AccuracyRadius)
+	geo.AccuracyRadius = &radius
+	if record.Location.TimeZone != "" {
+		tz := record.Location.TimeZone
+		geo.Timezone = &tz
+	}
+	if record.Postal.Code != "" {
+		postal := record.Postal.Code
+		geo.PostalCode = &postal
+	}
+	isInEU := record.RegisteredCountry.IsInEuropeanUnion
+	geo.IsInEu = &isInEU
+
+	return geo, nil
+}
+
+// WithFallback configures provider as the offline fallback used by
+// Client.LookupOffline. Any LookupProvider can be supplied, not just the
+// MaxMind-backed one returned by NewLocalDatabase.
+func WithFallback(provider LookupProvider) ClientOption {
+	return func(c *Client) error {
+		c.fallback = provider
+		return nil
+	}
+}
+
+// WithLocalDatabase is a convenience wrapper around WithFallback that
+// opens a MaxMind .mmdb file (e.g. GeoLite2-City) as the fallback
+// provider.
+func WithLocalDatabase(path string) ClientOption {
+	return func(c *Client) error {
+		provider, err := NewLocalDatabase(path)
+		if err != nil {
+			return err
+		}
+		c.fallback = provider
+		return nil
+	}
+}
+
+// LookupOffline resolves ip entirely from the configured fallback
+// provider, without contacting the ip-sonar API. Use it to keep serving
+// lookups during an outage or once an API quota has been exhausted; see
+// WithLocalDatabase and WithFallback.
+func (c *Client) LookupOffline(ctx context.Context, ip string) (*IPGeolocation, error) {
+	if c.fallback == nil {
+		return nil, fmt.Errorf("ipsonar: no fallback provider configured; use WithLocalDatabase or WithFallback")
+	}
+	return c.fallback.Lookup(ctx, ip)
+}
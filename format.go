@@ -0,0 +1,234 @@
+package ipsonar
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// textFieldSetters maps IPGeolocation's `key: value` plain-text field
+// names to a setter for that field. It is also used, keyed by CSV header,
+// to decode batch CSV responses.
+var textFieldSetters = map[string]func(*IPGeolocation, string) error{
+	"ip":                func(g *IPGeolocation, v string) error { g.IP = strPtr(v); return nil },
+	"country_code":      func(g *IPGeolocation, v string) error { g.CountryCode = strPtr(v); return nil },
+	"country_name":      func(g *IPGeolocation, v string) error { g.CountryName = strPtr(v); return nil },
+	"city_name":         func(g *IPGeolocation, v string) error { g.CityName = strPtr(v); return nil },
+	"continent_code":    func(g *IPGeolocation, v string) error { g.ContinentCode = strPtr(v); return nil },
+	"continent_name":    func(g *IPGeolocation, v string) error { g.ContinentName = strPtr(v); return nil },
+	"timezone":          func(g *IPGeolocation, v string) error { g.Timezone = strPtr(v); return nil },
+	"postal_code":       func(g *IPGeolocation, v string) error { g.PostalCode = strPtr(v); return nil },
+	"subdivision1_code": func(g *IPGeolocation, v string) error { g.Subdivision1Code = strPtr(v); return nil },
+	"subdivision1_name": func(g *IPGeolocation, v string) error { g.Subdivision1Name = strPtr(v); return nil },
+	"subdivision2_code": func(g *IPGeolocation, v string) error { g.Subdivision2Code = strPtr(v); return nil },
+	"subdivision2_name": func(g *IPGeolocation, v string) error { g.Subdivision2Name = strPtr(v); return nil },
+	"latitude": func(g *IPGeolocation, v string) error {
+		f, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			return err
+		}
+		g.Latitude = float32Ptr(float32(f))
+		return nil
+	},
+	"longitude": func(g *IPGeolocation, v string) error {
+		f, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			return err
+		}
+		g.Longitude = float32Ptr(float32(f))
+		return nil
+	},
+	"accuracy_radius": func(g *IPGeolocation, v string) error {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return err
+		}
+		g.AccuracyRadius = int32Ptr(int32(n))
+		return nil
+	},
+	"is_in_eu": func(g *IPGeolocation, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		g.IsInEu = boolPtr(b)
+		return nil
+	},
+}
+
+func strPtr(v string) *string       { return &v }
+func float32Ptr(v float32) *float32 { return &v }
+func int32Ptr(v int32) *int32       { return &v }
+func boolPtr(v bool) *bool          { return &v }
+
+// ParseIPGeolocationText decodes a plain-text response body into an
+// IPGeolocation. The body may be a single bare value (when the caller
+// requested exactly one field) or newline-delimited "key: value" pairs.
+func ParseIPGeolocationText(body []byte, params *LookupParams) (*IPGeolocation, error) {
+	text := strings.TrimSpace(string(body))
+	if text == "" {
+		return &IPGeolocation{}, nil
+	}
+
+	geo := &IPGeolocation{}
+
+	if !strings.Contains(text, ":") || !strings.Contains(text, "\n") {
+		if field := soleRequestedField(params); field != "" {
+			setter, ok := textFieldSetters[field]
+			if !ok {
+				return nil, fmt.Errorf("ipsonar: unknown field %q in plain-text response", field)
+			}
+			if err := setter(geo, text); err != nil {
+				return nil, fmt.Errorf("ipsonar: parse field %q: %w", field, err)
+			}
+			return geo, nil
+		}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("ipsonar: malformed plain-text line %q", line)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		setter, ok := textFieldSetters[key]
+		if !ok {
+			continue
+		}
+		if err := setter(geo, value); err != nil {
+			return nil, fmt.Errorf("ipsonar: parse field %q: %w", key, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ipsonar: scan plain-text response: %w", err)
+	}
+
+	return geo, nil
+}
+
+// soleRequestedField returns the single field name requested via
+// LookupParams.Fields, or "" if zero or more than one field was requested.
+func soleRequestedField(params *LookupParams) string {
+	if params == nil || params.Fields == nil {
+		return ""
+	}
+	fields := strings.Split(*params.Fields, ",")
+	if len(fields) != 1 {
+		return ""
+	}
+	return strings.TrimSpace(fields[0])
+}
+
+// ParseIPGeolocationCSV decodes a text/csv batch response body, mapping
+// the header row's column names onto IPGeolocation fields.
+func ParseIPGeolocationCSV(body []byte) ([]IPGeolocation, error) {
+	reader := csv.NewReader(strings.NewReader(string(body)))
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("ipsonar: decode csv response: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	header := rows[0]
+	result := make([]IPGeolocation, 0, len(rows)-1)
+
+	for _, row := range rows[1:] {
+		geo := IPGeolocation{}
+		for i, column := range header {
+			if i >= len(row) || row[i] == "" {
+				continue
+			}
+			setter, ok := textFieldSetters[strings.TrimSpace(column)]
+			if !ok {
+				continue
+			}
+			if err := setter(&geo, row[i]); err != nil {
+				return nil, fmt.Errorf("ipsonar: parse column %q: %w", column, err)
+			}
+		}
+		result = append(result, geo)
+	}
+
+	return result, nil
+}
+
+// LookupTextWithResponse requests a text/plain response (via an Accept
+// header request editor) and decodes the result into an IPGeolocation.
+// It bypasses any configured Cache, which only ever stores JSON results.
+// Non-200 responses are reported as an error carrying the status code.
+func (c *ClientWithResponses) LookupTextWithResponse(ctx context.Context, ip string, params *LookupParams, reqEditors ...RequestEditorFn) (*IPGeolocation, error) {
+	reqEditors = append([]RequestEditorFn{acceptHeader("text/plain")}, reqEditors...)
+
+	rsp, err := c.ClientInterface.LookupRaw(ctx, ip, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		parsed, err := ParseLookupResponse(rsp)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("ipsonar: lookup returned status %d", parsed.StatusCode())
+	}
+
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("ipsonar: read lookup response body: %w", err)
+	}
+
+	return ParseIPGeolocationText(body, params)
+}
+
+// BatchLookupCSVWithResponse requests a text/csv response and decodes
+// the result into a slice of IPGeolocation. It bypasses any configured
+// Cache, which only ever stores JSON results. Non-200 responses are
+// reported as an error carrying the status code.
+func (c *ClientWithResponses) BatchLookupCSVWithResponse(ctx context.Context, params *BatchLookupParams, body BatchLookupJSONRequestBody, reqEditors ...RequestEditorFn) ([]IPGeolocation, error) {
+	reqEditors = append([]RequestEditorFn{acceptHeader("text/csv")}, reqEditors...)
+
+	rsp, err := c.ClientInterface.BatchLookupRaw(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		parsed, err := ParseBatchLookupResponse(rsp)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("ipsonar: batch lookup returned status %d", parsed.StatusCode())
+	}
+
+	respBody, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("ipsonar: read batch lookup response body: %w", err)
+	}
+
+	return ParseIPGeolocationCSV(respBody)
+}
+
+// acceptHeader returns a RequestEditorFn that sets the Accept header,
+// used to content-negotiate a non-JSON response format.
+func acceptHeader(value string) RequestEditorFn {
+	return func(_ context.Context, req *http.Request) error {
+		req.Header.Set("Accept", value)
+		return nil
+	}
+}
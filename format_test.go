@@ -0,0 +1,163 @@
+package ipsonar_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	. "github.com/ip-sonar/ip-sonar-go"
+)
+
+func TestParseIPGeolocationText_SingleField(t *testing.T) {
+	params := &LookupParams{Fields: ptr("country_code")}
+
+	got, err := ParseIPGeolocationText([]byte("US"), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(&IPGeolocation{CountryCode: ptr("US")}, got); diff != "" {
+		t.Errorf("unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseIPGeolocationText_MultipleFields(t *testing.T) {
+	body := "ip: 1.2.3.4\ncountry_code: US\nlatitude: 40.7128\nis_in_eu: false\n"
+
+	got, err := ParseIPGeolocationText([]byte(body), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := &IPGeolocation{
+		IP:          ptr("1.2.3.4"),
+		CountryCode: ptr("US"),
+		Latitude:    ptr(float32(40.7128)),
+		IsInEu:      ptr(false),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseIPGeolocationText_MalformedLine(t *testing.T) {
+	_, err := ParseIPGeolocationText([]byte("ip: 1.2.3.4\nnot a valid line\n"), nil)
+	if err == nil {
+		t.Fatal("expected an error for a malformed line but got none")
+	}
+}
+
+func TestParseIPGeolocationText_Empty(t *testing.T) {
+	got, err := ParseIPGeolocationText([]byte("  \n"), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(&IPGeolocation{}, got); diff != "" {
+		t.Errorf("unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseIPGeolocationCSV(t *testing.T) {
+	body := "ip,country_code,accuracy_radius\n1.2.3.4,US,50\n5.6.7.8,DE,10\n"
+
+	got, err := ParseIPGeolocationCSV([]byte(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []IPGeolocation{
+		{IP: ptr("1.2.3.4"), CountryCode: ptr("US"), AccuracyRadius: ptr(int32(50))},
+		{IP: ptr("5.6.7.8"), CountryCode: ptr("DE"), AccuracyRadius: ptr(int32(10))},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseIPGeolocationCSV_SkipsUnknownColumnsAndEmptyValues(t *testing.T) {
+	body := "ip,mystery_column,city_name\n1.2.3.4,whatever,\n"
+
+	got, err := ParseIPGeolocationCSV([]byte(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []IPGeolocation{{IP: ptr("1.2.3.4")}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestLookupTextWithResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if accept := r.Header.Get("Accept"); accept != "text/plain" {
+			t.Errorf("expected Accept: text/plain, got %q", accept)
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("US"))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithResponses(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	params := &LookupParams{Fields: ptr("country_code")}
+	got, err := client.LookupTextWithResponse(context.Background(), "1.2.3.4", params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(&IPGeolocation{CountryCode: ptr("US")}, got); diff != "" {
+		t.Errorf("unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestLookupTextWithResponse_NonOKStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"not found"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithResponses(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.LookupTextWithResponse(context.Background(), "1.2.3.4", nil)
+	if err == nil {
+		t.Fatal("expected an error for a 404 response but got none")
+	}
+}
+
+func TestBatchLookupCSVWithResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if accept := r.Header.Get("Accept"); accept != "text/csv" {
+			t.Errorf("expected Accept: text/csv, got %q", accept)
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Write([]byte("ip,country_code\n1.2.3.4,US\n5.6.7.8,DE\n"))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithResponses(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	got, err := client.BatchLookupCSVWithResponse(context.Background(), nil, BatchLookupJSONRequestBody{Data: []string{"1.2.3.4", "5.6.7.8"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []IPGeolocation{
+		{IP: ptr("1.2.3.4"), CountryCode: ptr("US")},
+		{IP: ptr("5.6.7.8"), CountryCode: ptr("DE")},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected result (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,68 @@
+// Package otel adapts ipsonar.Observer to OpenTelemetry spans.
+package otel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	ipsonar "github.com/ip-sonar/ip-sonar-go"
+)
+
+// Observer is an ipsonar.Observer that records a span per request and
+// per cache consultation. For W3C trace context propagation onto the
+// outgoing HTTP request itself, pair it with an http.Client built on
+// otelhttp.NewTransport, passed to ipsonar.WithHTTPClient.
+type Observer struct {
+	tracer trace.Tracer
+}
+
+var _ ipsonar.Observer = (*Observer)(nil)
+
+// New creates an Observer whose spans are attributed to tracerName,
+// typically the importing module's path.
+func New(tracerName string) *Observer {
+	return &Observer{tracer: otel.Tracer(tracerName)}
+}
+
+// ObserveRequest implements ipsonar.Observer, recording a span that
+// covers the request's measured duration, parented onto ctx so it nests
+// under whatever span the caller already had open.
+func (o *Observer) ObserveRequest(ctx context.Context, method, endpoint string, status int, dur time.Duration, err error) {
+	end := time.Now()
+	start := end.Add(-dur)
+
+	_, span := o.tracer.Start(ctx, "ipsonar."+endpoint,
+		trace.WithTimestamp(start),
+		trace.WithAttributes(
+			attribute.String("http.method", method),
+			attribute.String("ipsonar.endpoint", endpoint),
+			attribute.Int("http.status_code", status),
+		),
+	)
+	defer span.End(trace.WithTimestamp(end))
+
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	case status >= 400:
+		span.SetStatus(codes.Error, "")
+	}
+}
+
+// ObserveCacheEvent implements ipsonar.Observer, recording a span
+// representing a single cache consultation, parented onto ctx.
+func (o *Observer) ObserveCacheEvent(ctx context.Context, endpoint string, hit bool) {
+	_, span := o.tracer.Start(ctx, "ipsonar.cache."+endpoint,
+		trace.WithAttributes(
+			attribute.String("ipsonar.endpoint", endpoint),
+			attribute.Bool("ipsonar.cache", hit),
+		),
+	)
+	span.End()
+}
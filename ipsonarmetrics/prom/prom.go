@@ -0,0 +1,97 @@
+// Package prom adapts ipsonar.Observer to Prometheus metrics.
+package prom
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	ipsonar "github.com/ip-sonar/ip-sonar-go"
+)
+
+// Observer is an ipsonar.Observer backed by Prometheus metrics. Create
+// one with New, register it with a prometheus.Registerer, and pass it to
+// ipsonar.WithObserver.
+type Observer struct {
+	requestsTotal   *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	retriesTotal    *prometheus.CounterVec
+	rateLimited     *prometheus.CounterVec
+	cacheEvents     *prometheus.CounterVec
+}
+
+var _ ipsonar.Observer = (*Observer)(nil)
+
+// New creates an Observer and registers its metrics with reg.
+func New(reg prometheus.Registerer) *Observer {
+	o := &Observer{
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "client_requests_total",
+			Help: "Total ip-sonar API requests, by endpoint and status class.",
+		}, []string{"endpoint", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "client_request_duration_seconds",
+			Help:    "ip-sonar API request latency in seconds, by endpoint.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"endpoint"}),
+		retriesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "client_retries_total",
+			Help: "Total ip-sonar API attempts with a retriable outcome (5xx, 429 or network error), by endpoint.",
+		}, []string{"endpoint"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "client_rate_limited_total",
+			Help: "Total ip-sonar API requests rejected with 429, by endpoint.",
+		}, []string{"endpoint"}),
+		cacheEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "client_cache_events_total",
+			Help: "Total lookup cache consultations, by endpoint and outcome.",
+		}, []string{"endpoint", "outcome"}),
+	}
+
+	reg.MustRegister(o.requestsTotal, o.requestDuration, o.retriesTotal, o.rateLimited, o.cacheEvents)
+
+	return o
+}
+
+// ObserveRequest implements ipsonar.Observer.
+func (o *Observer) ObserveRequest(_ context.Context, method, endpoint string, status int, dur time.Duration, err error) {
+	o.requestDuration.WithLabelValues(endpoint).Observe(dur.Seconds())
+	o.requestsTotal.WithLabelValues(endpoint, statusClass(status, err)).Inc()
+
+	// ObserveRequest fires once per attempt; a status the retry policy
+	// treats as retriable (5xx, 429, or a network error) implies this
+	// attempt will be retried unless the client's max attempts are
+	// already exhausted, which this counter cannot distinguish.
+	if status == 429 || status >= 500 || err != nil {
+		o.retriesTotal.WithLabelValues(endpoint).Inc()
+	}
+	if status == 429 {
+		o.rateLimited.WithLabelValues(endpoint).Inc()
+	}
+}
+
+// ObserveCacheEvent implements ipsonar.Observer.
+func (o *Observer) ObserveCacheEvent(_ context.Context, endpoint string, hit bool) {
+	outcome := "miss"
+	if hit {
+		outcome = "hit"
+	}
+	o.cacheEvents.WithLabelValues(endpoint, outcome).Inc()
+}
+
+func statusClass(status int, err error) string {
+	switch {
+	case err != nil:
+		return "error"
+	case status >= 500:
+		return "5xx"
+	case status >= 400:
+		return "4xx"
+	case status >= 200:
+		return "2xx"
+	default:
+		return fmt.Sprintf("%d", status)
+	}
+}
@@ -0,0 +1,74 @@
+package ipsonar
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Observer receives notifications about Client activity, for metrics and
+// tracing integrations. Implementations must be safe for concurrent use.
+// See the ipsonarmetrics/prom and ipsonarmetrics/otel subpackages for
+// ready-made adapters.
+type Observer interface {
+	// ObserveRequest is called once per HTTP attempt: a single retry
+	// produces two calls, and a chunked BatchLookupAll produces one call
+	// per chunk. ctx is the context passed to the triggering Client
+	// method call, so tracing adapters can parent a span onto it. err is
+	// non-nil on network failure; status is 0 in that case.
+	ObserveRequest(ctx context.Context, method, endpoint string, status int, dur time.Duration, err error)
+	// ObserveCacheEvent is called once per cache lookup performed by
+	// Lookup or BatchLookup, with the context passed to that call.
+	ObserveCacheEvent(ctx context.Context, endpoint string, hit bool)
+}
+
+// WithObserver wires observer into the Client's HTTP doer, so every
+// request is reported, and into the cache lookup path.
+//
+// Options are applied in order, each wrapping the current doer, so
+// placement relative to WithRetry matters: apply WithObserver before
+// WithRetry to observe every retry attempt individually, or after it to
+// observe only the final outcome of each call.
+func WithObserver(observer Observer) ClientOption {
+	return func(c *Client) error {
+		if c.Client == nil {
+			c.Client = &http.Client{}
+		}
+		c.Client = &observingDoer{next: c.Client, observer: observer}
+		c.observer = observer
+		return nil
+	}
+}
+
+type observingDoer struct {
+	next     HttpRequestDoer
+	observer Observer
+}
+
+func (d *observingDoer) Do(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := d.next.Do(req)
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	d.observer.ObserveRequest(req.Context(), req.Method, endpointForRequest(req), status, time.Since(start), err)
+
+	return resp, err
+}
+
+// endpointForRequest classifies a request by its path shape rather than
+// its exact path, which for Lookup includes the looked-up IP.
+func endpointForRequest(req *http.Request) string {
+	path := strings.TrimSuffix(req.URL.Path, "/")
+	switch {
+	case strings.HasSuffix(path, "/my"):
+		return "lookup_my"
+	case strings.HasSuffix(path, "/batch"):
+		return "batch_lookup"
+	default:
+		return "lookup"
+	}
+}
@@ -0,0 +1,210 @@
+package ipsonar
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how a Client retries failed requests.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first.
+	// A value <= 1 disables retries.
+	MaxAttempts int
+	// BaseDelay is the starting delay for exponential backoff. Defaults
+	// to 200ms if zero.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff delay. Defaults to 30s if zero.
+	MaxDelay time.Duration
+	// Stats, if set, is notified after every attempt.
+	Stats RetryStats
+}
+
+// RetryStats receives per-attempt outcomes from a retrying Client, so
+// callers can emit metrics without polling.
+type RetryStats interface {
+	// ObserveAttempt is called once per attempt. status is 0 for network
+	// errors. attempt is 1-indexed.
+	ObserveAttempt(attempt int, status int, err error)
+}
+
+func (p RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) baseDelay() time.Duration {
+	if p.BaseDelay <= 0 {
+		return 200 * time.Millisecond
+	}
+	return p.BaseDelay
+}
+
+func (p RetryPolicy) maxDelay() time.Duration {
+	if p.MaxDelay <= 0 {
+		return 30 * time.Second
+	}
+	return p.MaxDelay
+}
+
+// retryingDoer wraps an HttpRequestDoer, retrying requests per policy.
+type retryingDoer struct {
+	next   HttpRequestDoer
+	policy RetryPolicy
+}
+
+// WithRetry wraps the Client's HTTP doer so that requests are retried on
+// network errors, 5xx responses, and 429s, honoring any Retry-After
+// header and otherwise backing off exponentially with jitter.
+func WithRetry(policy RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		if c.Client == nil {
+			c.Client = &http.Client{}
+		}
+		c.Client = &retryingDoer{next: c.Client, policy: policy}
+		return nil
+	}
+}
+
+func (d *retryingDoer) Do(req *http.Request) (*http.Response, error) {
+	body, err := bufferBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *http.Response
+	attempts := d.policy.maxAttempts()
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			if err := rewindBody(req, body); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err = d.next.Do(req)
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		if d.policy.Stats != nil {
+			d.policy.Stats.ObserveAttempt(attempt, status, err)
+		}
+
+		if !isRetriable(resp, err) || attempt == attempts {
+			return resp, err
+		}
+
+		delay := retryDelay(resp, d.policy, attempt)
+		drainAndClose(resp)
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+
+	return resp, err
+}
+
+func isRetriable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	if resp == nil {
+		return false
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryDelay determines how long to wait before the next attempt,
+// preferring a Retry-After header when the server sent one.
+func retryDelay(resp *http.Response, policy RetryPolicy, attempt int) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return d
+		}
+	}
+	return backoffWithJitter(policy, attempt)
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			seconds = 0
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func backoffWithJitter(policy RetryPolicy, attempt int) time.Duration {
+	base := policy.baseDelay()
+	max := policy.maxDelay()
+
+	delay := base << uint(attempt-1)
+	if delay <= 0 || delay > max {
+		delay = max
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(delay) + 1))
+	return jitter
+}
+
+// bufferBody reads req.Body into memory (if present) and installs
+// req.GetBody so the body can be safely replayed across attempts.
+func bufferBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	req.Body, _ = req.GetBody()
+
+	return data, nil
+}
+
+func rewindBody(req *http.Request, _ []byte) error {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
+
+// drainAndClose discards and closes a response body so the underlying
+// connection can be reused before the next attempt.
+func drainAndClose(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
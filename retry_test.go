@@ -0,0 +1,248 @@
+package ipsonar_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/ip-sonar/ip-sonar-go"
+)
+
+// countingStats records every ObserveAttempt call for assertions.
+type countingStats struct {
+	attempts []struct {
+		attempt int
+		status  int
+		err     error
+	}
+}
+
+func (s *countingStats) ObserveAttempt(attempt int, status int, err error) {
+	s.attempts = append(s.attempts, struct {
+		attempt int
+		status  int
+		err     error
+	}{attempt, status, err})
+}
+
+func TestWithRetry_RetriesOn5xxThenSucceeds(t *testing.T) {
+	var requestCount int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if requestCount < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ip":"1.2.3.4"}`))
+	}))
+	defer server.Close()
+
+	stats := &countingStats{}
+	client, err := NewClient(server.URL, WithRetry(RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+		Stats:       stats,
+	}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.Lookup(context.Background(), "1.2.3.4", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+	if requestCount != 3 {
+		t.Errorf("expected 3 requests, got %d", requestCount)
+	}
+	if len(stats.attempts) != 3 {
+		t.Fatalf("expected 3 observed attempts, got %d", len(stats.attempts))
+	}
+	if stats.attempts[2].status != http.StatusOK {
+		t.Errorf("expected final attempt status 200, got %d", stats.attempts[2].status)
+	}
+}
+
+func TestWithRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	var requestCount int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, WithRetry(RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+	}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.Lookup(context.Background(), "1.2.3.4", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", resp.StatusCode)
+	}
+	if requestCount != 3 {
+		t.Errorf("expected exactly 3 requests (MaxAttempts), got %d", requestCount)
+	}
+}
+
+func TestWithRetry_DoesNotRetryOn4xx(t *testing.T) {
+	var requestCount int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, WithRetry(RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   time.Millisecond,
+	}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.Lookup(context.Background(), "1.2.3.4", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if requestCount != 1 {
+		t.Errorf("expected exactly 1 request for a non-retriable status, got %d", requestCount)
+	}
+}
+
+func TestWithRetry_HonorsRetryAfterDeltaSeconds(t *testing.T) {
+	var requestCount int
+	var gotDelay time.Duration
+	var firstRequestAt time.Time
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if requestCount == 1 {
+			firstRequestAt = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		gotDelay = time.Since(firstRequestAt)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, WithRetry(RetryPolicy{
+		MaxAttempts: 2,
+		BaseDelay:   time.Millisecond,
+	}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.Lookup(context.Background(), "1.2.3.4", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if gotDelay < time.Second {
+		t.Errorf("expected the retry to wait at least the 1s Retry-After delay, waited %v", gotDelay)
+	}
+}
+
+func TestWithRetry_RewindsRequestBody(t *testing.T) {
+	var bodies []string
+	var requestCount int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if requestCount < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, WithRetry(RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+	}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.BatchLookup(context.Background(), nil, BatchLookupJSONRequestBody{Data: []string{"1.2.3.4"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(bodies))
+	}
+	if bodies[0] != bodies[1] {
+		t.Errorf("expected the replayed body to match the original, got %q then %q", bodies[0], bodies[1])
+	}
+}
+
+func TestWithRetry_NetworkErrorIsRetriable(t *testing.T) {
+	doer := &flakyDoer{failuresBeforeSuccess: 2}
+
+	client, err := NewClient("https://api.example.com", WithHTTPClient(doer), WithRetry(RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+	}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.Lookup(context.Background(), "1.2.3.4", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if doer.calls != 3 {
+		t.Errorf("expected 3 calls (2 failures + 1 success), got %d", doer.calls)
+	}
+}
+
+// flakyDoer fails with a network error a fixed number of times before
+// succeeding, used to exercise retry-on-transport-error.
+type flakyDoer struct {
+	calls                 int
+	failuresBeforeSuccess int
+}
+
+func (d *flakyDoer) Do(req *http.Request) (*http.Response, error) {
+	d.calls++
+	if d.calls <= d.failuresBeforeSuccess {
+		return nil, errors.New("connection reset")
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     make(http.Header),
+		Body:       io.NopCloser(http.NoBody),
+	}, nil
+}